@@ -145,6 +145,10 @@ func Unmarshal(data []byte, destStruct ygot.GoStruct, opts ...ytypes.UnmarshalOp
 	if !ok {
 		return fmt.Errorf("could not find schema for type %s", tn)
 	}
+	data, err := ytypes.PreprocessJSON(data, opts...)
+	if err != nil {
+		return err
+	}
 	var jsonTree interface{}
 	if err := json.Unmarshal([]byte(data), &jsonTree); err != nil {
 		return err
@@ -0,0 +1,53 @@
+// Copyright 2023 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// DiffJSONAgainstStruct unmarshals jsonDoc, which must be RFC7951 JSON, into
+// a new GoStruct of the same type as modified using the Unmarshal function of
+// the supplied schema, and returns the ygot.Diff between the unmarshalled
+// struct, as the original, and modified. The supplied DiffOpts are passed to
+// ygot.Diff.
+func DiffJSONAgainstStruct(schema *Schema, jsonDoc []byte, modified ygot.GoStruct, opts ...ygot.DiffOpt) (*gpb.Notification, error) {
+	if schema == nil || schema.Unmarshal == nil {
+		return nil, fmt.Errorf("schema must be non-nil and have an Unmarshal function")
+	}
+	if util.IsValueNil(modified) || !util.IsValueStructPtr(reflect.ValueOf(modified)) {
+		return nil, fmt.Errorf("modified must be a non-nil struct pointer, got %T", modified)
+	}
+
+	original, ok := reflect.New(reflect.TypeOf(modified).Elem()).Interface().(ygot.GoStruct)
+	if !ok {
+		return nil, fmt.Errorf("cannot create new GoStruct of type %T", modified)
+	}
+	if err := schema.Unmarshal(jsonDoc, original); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal JSON document into %T: %v", original, err)
+	}
+
+	n, err := ygot.Diff(original, modified, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot diff JSON document against %T: %v", modified, err)
+	}
+	return n, nil
+}
@@ -54,6 +54,41 @@ func TestValidateLeafRefDataOrderedMap(t *testing.T) {
 				return om
 			}(),
 		},
+	}, {
+		desc:     "checking short leafref to key of nested ordered list entry",
+		inSchema: ctestschema.SchemaTree["Device"],
+		in: &ctestschema.Device{
+			OrderedList: func() *ctestschema.OrderedList_OrderedMap {
+				om := &ctestschema.OrderedList_OrderedMap{}
+				for _, k := range []string{"foo", "bar"} {
+					if _, err := om.AppendNew(k); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if _, err := om.Get("bar").AppendNewOrderedList("baz"); err != nil {
+					t.Fatal(err)
+				}
+				om.Get("foo").ParentKey = ygot.String("baz")
+				return om
+			}(),
+		},
+		wantErr: `field name ParentKey value baz (string ptr) schema path /device/ordered-lists/ordered-list/state/parent-key has leafref path ../../../ordered-list/key not equal to any target nodes`,
+	}, {
+		desc:     "checking short leafref to key of missing ordered list entry",
+		inSchema: ctestschema.SchemaTree["Device"],
+		in: &ctestschema.Device{
+			OrderedList: func() *ctestschema.OrderedList_OrderedMap {
+				om := &ctestschema.OrderedList_OrderedMap{}
+				for _, k := range []string{"foo", "bar"} {
+					if _, err := om.AppendNew(k); err != nil {
+						t.Fatal(err)
+					}
+				}
+				om.Get("foo").ParentKey = ygot.String("baz")
+				return om
+			}(),
+		},
+		wantErr: `field name ParentKey value baz (string ptr) schema path /device/ordered-lists/ordered-list/state/parent-key has leafref path ../../../ordered-list/key not equal to any target nodes`,
 	}, {
 		desc:     "checking long leafref",
 		inSchema: ctestschema.SchemaTree["Device"],
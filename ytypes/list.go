@@ -55,7 +55,11 @@ func validateList(schema *yang.Entry, value interface{}) util.Errors {
 	checkMapElement := func(key, val reflect.Value) {
 		structElems := val.Elem()
 		// Check that keys are present and have correct values.
-		errors = util.AppendErrs(errors, checkKeys(schema, structElems, key))
+		if errs := checkKeysPresent(schema, structElems, key); errs != nil {
+			errors = util.AppendErrs(errors, errs)
+		} else {
+			errors = util.AppendErrs(errors, checkKeys(schema, structElems, key))
+		}
 
 		// Verify each elements's fields.
 		errors = util.AppendErrs(errors, validateStructElems(schema, val.Interface()))
@@ -114,6 +118,65 @@ func checkKeys(schema *yang.Entry, structElems reflect.Value, keyValue reflect.V
 	return checkStructKeyValues(structElems, keyValue)
 }
 
+// isUnsetEnum reports whether v is an enumerated value with the zero (UNSET)
+// value. Enumerated key leaves are stored by value, such that an unset key
+// leaf is not nil.
+func isUnsetEnum(v reflect.Value) bool {
+	if _, ok := v.Interface().(ygot.GoEnum); !ok {
+		return false
+	}
+	// If the value is a simple union enum, then extract the underlying
+	// enum value from the interface.
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	return v.Int() == 0
+}
+
+// checkKeysPresent checks that each of the key leaves of the list element
+// structElems, whose map key is keyValue, is set. For a list schema with a
+// single key, the key field is found by its schema name, whereas for a list
+// schema with multiple keys, the key fields are named by the fields of the
+// key struct. An enumerated key leaf with the zero (UNSET) value is treated
+// as not set. Errors identify the key leaf by its schema name where known.
+func checkKeysPresent(schema *yang.Entry, structElems reflect.Value, keyValue reflect.Value) util.Errors {
+	// keyFields maps the name of each key field in the element struct to
+	// the name of the key leaf used in error messages.
+	keyFields := map[string]string{}
+	var keyFieldNames []string
+	switch keys := strings.Fields(schema.Key); {
+	case len(keys) == 1:
+		keyFieldName, err := schemaNameToFieldName(structElems, schema.Key)
+		if err != nil {
+			return util.NewErrs(err)
+		}
+		keyFieldNames = append(keyFieldNames, keyFieldName)
+		keyFields[keyFieldName] = schema.Key
+	case keyValue.Kind() == reflect.Struct:
+		for i := 0; i < keyValue.NumField(); i++ {
+			f := keyValue.Type().Field(i)
+			keyFieldNames = append(keyFieldNames, f.Name)
+			keyFields[f.Name] = f.Name
+			if p, ok := f.Tag.Lookup("path"); ok {
+				keyFields[f.Name] = p
+			}
+		}
+	}
+
+	var errors []error
+	for _, keyFieldName := range keyFieldNames {
+		fv := structElems.FieldByName(keyFieldName)
+		if !fv.IsValid() {
+			// Missing key fields are reported by checkKeys.
+			continue
+		}
+		if util.IsValueNil(fv.Interface()) || isUnsetEnum(fv) {
+			errors = util.AppendErr(errors, fmt.Errorf("list %s entry with key %v is missing key leaf %s", schema.Name, keyValue.Interface(), keyFields[keyFieldName]))
+		}
+	}
+	return errors
+}
+
 // checkBasicKeyValue checks if keyValue, which is the value of the map key,
 // is equal to the value of the key field with field name keyFieldName in the
 // element struct.
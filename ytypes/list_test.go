@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -246,9 +247,10 @@ func TestValidateListSimpleKey(t *testing.T) {
 	}
 
 	tests := []struct {
-		desc    string
-		val     interface{}
-		wantErr bool
+		desc             string
+		val              interface{}
+		wantErr          bool
+		wantErrSubstring string
 	}{
 		{
 			desc: "success",
@@ -277,6 +279,16 @@ func TestValidateListSimpleKey(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			desc: "nil key leaf",
+			val: map[string]*StringListElemStruct{
+				"elem1_key_val": {
+					LeafName: ygot.String("elem1_leaf_name"),
+				},
+			},
+			wantErr:          true,
+			wantErrSubstring: "list list-schema entry with key elem1_key_val is missing key leaf keyfield-name",
+		},
 	}
 
 	for _, tt := range tests {
@@ -285,6 +297,9 @@ func TestValidateListSimpleKey(t *testing.T) {
 			if got, want := (errs != nil), tt.wantErr; got != want {
 				t.Errorf("%s: b.Validate(%v) got error: %v, want error? %v", tt.desc, tt.val, errs, tt.wantErr)
 			}
+			if got := errs.String(); !strings.Contains(got, tt.wantErrSubstring) {
+				t.Errorf("%s: b.Validate(%v) got error: %s, want error containing: %s", tt.desc, tt.val, got, tt.wantErrSubstring)
+			}
 			testErrLog(t, tt.desc, errs)
 		})
 	}
@@ -317,8 +332,8 @@ func TestValidateListStructKey(t *testing.T) {
 	}
 
 	type KeyStruct struct {
-		Key1 string
-		Key2 int32
+		Key1 string `path:"key1"`
+		Key2 int32  `path:"key2"`
 	}
 	type StringListElemStruct struct {
 		Key1     *string `path:"key1"`
@@ -331,9 +346,10 @@ func TestValidateListStructKey(t *testing.T) {
 	}
 
 	tests := []struct {
-		desc    string
-		val     interface{}
-		wantErr bool
+		desc             string
+		val              interface{}
+		wantErr          bool
+		wantErrSubstring string
 	}{
 		{
 			desc: "success",
@@ -376,6 +392,17 @@ func TestValidateListStructKey(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			desc: "nil key leaf",
+			val: map[KeyStruct]*StringListElemStruct{
+				{"elem1_key_val", 1}: {
+					Key1:     ygot.String("elem1_key_val"),
+					LeafName: ygot.String("elem1_leaf_name"),
+				},
+			},
+			wantErr:          true,
+			wantErrSubstring: "list list-schema-struct-key entry with key {elem1_key_val 1} is missing key leaf key2",
+		},
 	}
 
 	for _, tt := range tests {
@@ -384,6 +411,9 @@ func TestValidateListStructKey(t *testing.T) {
 			if got, want := (errs != nil), tt.wantErr; got != want {
 				t.Errorf("%s: b.Validate(%v) got error: %v, want error? %v", tt.desc, tt.val, errs, tt.wantErr)
 			}
+			if got := errs.String(); !strings.Contains(got, tt.wantErrSubstring) {
+				t.Errorf("%s: b.Validate(%v) got error: %s, want error containing: %s", tt.desc, tt.val, got, tt.wantErrSubstring)
+			}
 			testErrLog(t, tt.desc, errs)
 		})
 	}
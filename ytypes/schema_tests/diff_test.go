@@ -0,0 +1,135 @@
+// Copyright 2023 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"testing"
+
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/ygot/exampleoc"
+	"github.com/openconfig/ygot/testutil"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/openconfig/ygot/ytypes"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestDiffJSONAgainstStruct(t *testing.T) {
+	desiredJSON := []byte(`{
+  "openconfig-interfaces:interfaces": {
+    "interface": [
+      {
+        "name": "eth0",
+        "config": {"name": "eth0", "mtu": 1500, "description": "uplink"}
+      }
+    ]
+  },
+  "openconfig-system:system": {
+    "config": {"hostname": "dev1"}
+  }
+}`)
+
+	live := &exampleoc.Device{}
+	live.GetOrCreateSystem().Hostname = ygot.String("dev2")
+	i := live.GetOrCreateInterface("eth0")
+	i.Mtu = ygot.Uint16(9000)
+	live.GetOrCreateInterface("eth1")
+
+	tests := []struct {
+		desc             string
+		inSchema         *ytypes.Schema
+		inJSON           []byte
+		inModified       ygot.GoStruct
+		inOpts           []ygot.DiffOpt
+		want             *gpb.Notification
+		wantErrSubstring string
+	}{{
+		desc:       "diff JSON desired state against live struct",
+		inSchema:   mustSchema(exampleoc.Schema),
+		inJSON:     desiredJSON,
+		inModified: live,
+		inOpts:     []ygot.DiffOpt{&ygot.DiffPathOpt{MapToSinglePath: true}},
+		want: &gpb.Notification{
+			Update: []*gpb.Update{{
+				Path: mustPath("/system/config/hostname"),
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "dev2"}},
+			}, {
+				Path: mustPath("/interfaces/interface[name=eth0]/config/mtu"),
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_UintVal{UintVal: 9000}},
+			}, {
+				Path: mustPath("/interfaces/interface[name=eth1]/name"),
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "eth1"}},
+			}},
+			Delete: []*gpb.Path{
+				mustPath("/interfaces/interface[name=eth0]/config/description"),
+			},
+		},
+	}, {
+		desc:       "identical JSON and struct",
+		inSchema:   mustSchema(exampleoc.Schema),
+		inJSON:     []byte(`{"openconfig-system:system": {"config": {"hostname": "dev2"}}}`),
+		inModified: &exampleoc.Device{System: &exampleoc.System{Hostname: ygot.String("dev2")}},
+		want:       &gpb.Notification{},
+	}, {
+		desc:       "non-root struct",
+		inSchema:   mustSchema(exampleoc.Schema),
+		inJSON:     []byte(`{"openconfig-system:config": {"hostname": "dev1"}}`),
+		inModified: &exampleoc.System{Hostname: ygot.String("dev2")},
+		want: &gpb.Notification{
+			Update: []*gpb.Update{{
+				Path: mustPath("/config/hostname"),
+				Val:  &gpb.TypedValue{Value: &gpb.TypedValue_StringVal{StringVal: "dev2"}},
+			}},
+		},
+	}, {
+		desc:             "invalid JSON",
+		inSchema:         mustSchema(exampleoc.Schema),
+		inJSON:           []byte(`{"openconfig-system:system": `),
+		inModified:       &exampleoc.Device{},
+		wantErrSubstring: "cannot unmarshal JSON document into *exampleoc.Device",
+	}, {
+		desc:             "JSON not matching schema",
+		inSchema:         mustSchema(exampleoc.Schema),
+		inJSON:           []byte(`{"openconfig-system:system": {"config": {"does-not-exist": "dev1"}}}`),
+		inModified:       &exampleoc.Device{},
+		wantErrSubstring: "cannot unmarshal JSON document into *exampleoc.Device",
+	}, {
+		desc:             "nil schema",
+		inJSON:           desiredJSON,
+		inModified:       &exampleoc.Device{},
+		wantErrSubstring: "schema must be non-nil",
+	}, {
+		desc:             "nil modified struct",
+		inSchema:         mustSchema(exampleoc.Schema),
+		inJSON:           desiredJSON,
+		inModified:       (*exampleoc.Device)(nil),
+		wantErrSubstring: "modified must be a non-nil struct pointer",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := ytypes.DiffJSONAgainstStruct(tt.inSchema, tt.inJSON, tt.inModified, tt.inOpts...)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("DiffJSONAgainstStruct: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if !testutil.NotificationSetEqual([]*gpb.Notification{got}, []*gpb.Notification{tt.want}) {
+				t.Errorf("DiffJSONAgainstStruct: did not get expected Notification, got: %v, want: %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,354 @@
+// Copyright 2023 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ygot/exampleoc"
+	"github.com/openconfig/ygot/integration_tests/schemaops/ctestschema"
+	"github.com/openconfig/ygot/uexampleoc"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/openconfig/ygot/ytypes"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestMinimalSetRequest(t *testing.T) {
+	baseDevice := func() *exampleoc.Device {
+		d := &exampleoc.Device{}
+		d.GetOrCreateSystem().Hostname = ygot.String("dev1")
+		d.GetOrCreateSystem().GetOrCreateDns().Search = []string{"a.example.com"}
+		i := d.GetOrCreateInterface("eth0")
+		i.Mtu = ygot.Uint16(1500)
+		i.Description = ygot.String("uplink")
+		i.GetOrCreateSubinterface(0).Description = ygot.String("sub0")
+		d.GetOrCreateInterface("eth1").Enabled = ygot.Bool(true)
+		return d
+	}
+
+	// attrSetDevice returns a device with a BGP attribute set whose keyless
+	// as-segment list has an entry for each of the supplied member lists.
+	attrSetDevice := func(members ...[]uint32) *exampleoc.Device {
+		d := &exampleoc.Device{}
+		as := d.GetOrCreateNetworkInstance("default").GetOrCreateProtocol(exampleoc.PolicyTypes_INSTALL_PROTOCOL_TYPE_BGP, "15169").GetOrCreateBgp().GetOrCreateRib().GetOrCreateAttrSet(1)
+		as.Med = ygot.Uint32(10)
+		for _, m := range members {
+			as.AsSegment = append(as.AsSegment, &exampleoc.NetworkInstance_Protocol_Bgp_Rib_AttrSet_AsSegment{Member: m})
+		}
+		return d
+	}
+
+	// propertyDevice returns a device with a component property whose value
+	// is v.
+	propertyDevice := func(v exampleoc.Component_Property_Value_Union) *exampleoc.Device {
+		d := &exampleoc.Device{}
+		d.GetOrCreateComponent("c1").GetOrCreateProperty("p").Value = v
+		return d
+	}
+
+	orderedMap := func(keys ...string) *ctestschema.OrderedList_OrderedMap {
+		om := &ctestschema.OrderedList_OrderedMap{}
+		for _, k := range keys {
+			v, err := om.AppendNew(k)
+			if err != nil {
+				t.Fatal(err)
+			}
+			v.Value = ygot.String(k + "-val")
+		}
+		return om
+	}
+
+	tests := []struct {
+		desc         string
+		inSchema     func() (*ytypes.Schema, error)
+		inCurrent    ygot.GoStruct
+		inDesired    ygot.GoStruct
+		wantDeletes  []string
+		wantReplaces []string
+		wantUpdates  []string
+		wantErr      bool
+	}{{
+		desc:      "identical structs",
+		inSchema:  exampleoc.Schema,
+		inCurrent: baseDevice(),
+		inDesired: baseDevice(),
+	}, {
+		desc:      "leaf and leaf-list changes",
+		inSchema:  exampleoc.Schema,
+		inCurrent: baseDevice(),
+		inDesired: func() *exampleoc.Device {
+			d := baseDevice()
+			d.System.Dns.Search = append(d.System.Dns.Search, "b.example.com")
+			d.Interface["eth0"].Description = nil
+			d.Interface["eth1"].Mtu = ygot.Uint16(9000)
+			return d
+		}(),
+		wantDeletes: []string{"/interfaces/interface[name=eth0]/config/description"},
+		wantUpdates: []string{"/interfaces/interface[name=eth1]/config/mtu", "/system/dns/config/search"},
+	}, {
+		desc:      "multiple changes within a container are collapsed into a replace",
+		inSchema:  exampleoc.Schema,
+		inCurrent: baseDevice(),
+		inDesired: func() *exampleoc.Device {
+			d := baseDevice()
+			d.System.Hostname = ygot.String("dev2")
+			d.System.Dns.Search = append(d.System.Dns.Search, "b.example.com")
+			d.Interface["eth0"].Mtu = ygot.Uint16(9000)
+			d.Interface["eth0"].Description = nil
+			return d
+		}(),
+		wantReplaces: []string{"/interfaces/interface[name=eth0]", "/system"},
+	}, {
+		desc:      "union bool leaf set to false",
+		inSchema:  exampleoc.Schema,
+		inCurrent: propertyDevice(exampleoc.UnionBool(true)),
+		inDesired: propertyDevice(exampleoc.UnionBool(false)),
+		wantUpdates: []string{
+			"/components/component[name=c1]/properties/property[name=p]/config/value",
+		},
+	}, {
+		desc:      "unset union bool leaf set to false",
+		inSchema:  exampleoc.Schema,
+		inCurrent: propertyDevice(nil),
+		inDesired: propertyDevice(exampleoc.UnionBool(false)),
+		wantUpdates: []string{
+			"/components/component[name=c1]/properties/property[name=p]/config/value",
+		},
+	}, {
+		desc:      "subtree additions are collapsed into replaces",
+		inSchema:  exampleoc.Schema,
+		inCurrent: baseDevice(),
+		inDesired: func() *exampleoc.Device {
+			d := baseDevice()
+			d.GetOrCreateSystem().GetOrCreateClock().TimezoneName = ygot.String("UTC")
+			i := d.GetOrCreateInterface("eth2")
+			i.Mtu = ygot.Uint16(9000)
+			i.GetOrCreateSubinterface(1).Description = ygot.String("sub1")
+			d.Interface["eth0"].GetOrCreateSubinterface(1).Description = ygot.String("sub1")
+			return d
+		}(),
+		wantReplaces: []string{
+			"/interfaces/interface[name=eth0]/subinterfaces/subinterface[index=1]",
+			"/interfaces/interface[name=eth2]",
+			"/system/clock",
+		},
+	}, {
+		desc:      "subtree removals are collapsed into deletes",
+		inSchema:  exampleoc.Schema,
+		inCurrent: baseDevice(),
+		inDesired: func() *exampleoc.Device {
+			d := baseDevice()
+			delete(d.Interface, "eth1")
+			d.Interface["eth0"].Subinterface = nil
+			d.System.Dns = nil
+			return d
+		}(),
+		wantDeletes: []string{
+			"/interfaces/interface[name=eth1]",
+			"/interfaces/interface[name=eth0]/subinterfaces/subinterface[index=0]",
+			"/system/dns",
+		},
+	}, {
+		desc:      "empty container in desired is treated as a removal",
+		inSchema:  exampleoc.Schema,
+		inCurrent: baseDevice(),
+		inDesired: func() *exampleoc.Device {
+			d := baseDevice()
+			d.System.Dns = &exampleoc.System_Dns{}
+			return d
+		}(),
+		wantDeletes: []string{"/system/dns"},
+	}, {
+		desc:      "multi-keyed list",
+		inSchema:  exampleoc.Schema,
+		inCurrent: &exampleoc.Device{},
+		inDesired: func() *exampleoc.Device {
+			d := &exampleoc.Device{}
+			ni := d.GetOrCreateNetworkInstance("default")
+			ni.GetOrCreateProtocol(exampleoc.PolicyTypes_INSTALL_PROTOCOL_TYPE_BGP, "15169")
+			return d
+		}(),
+		wantReplaces: []string{"/network-instances/network-instance[name=default]"},
+	}, {
+		desc:      "uncompressed schema",
+		inSchema:  uexampleoc.Schema,
+		inCurrent: &uexampleoc.Device{},
+		inDesired: func() *uexampleoc.Device {
+			d := &uexampleoc.Device{}
+			d.GetOrCreateInterfaces().GetOrCreateInterface("eth0").GetOrCreateConfig().Mtu = ygot.Uint16(1500)
+			return d
+		}(),
+		wantReplaces: []string{"/interfaces"},
+	}, {
+		desc:     "uncompressed schema list removed",
+		inSchema: uexampleoc.Schema,
+		inCurrent: func() *uexampleoc.Device {
+			d := &uexampleoc.Device{}
+			d.GetOrCreateInterfaces().GetOrCreateInterface("eth0").GetOrCreateConfig().Mtu = ygot.Uint16(1500)
+			d.GetOrCreateInterfaces().GetOrCreateInterface("eth1").GetOrCreateConfig().Mtu = ygot.Uint16(9000)
+			d.GetOrCreateSystem().GetOrCreateConfig().Hostname = ygot.String("dev1")
+			return d
+		}(),
+		inDesired: func() *uexampleoc.Device {
+			d := &uexampleoc.Device{}
+			d.GetOrCreateInterfaces()
+			d.GetOrCreateSystem().GetOrCreateConfig().Hostname = ygot.String("dev1")
+			return d
+		}(),
+		wantDeletes: []string{"/interfaces"},
+	}, {
+		desc:      "keyless list changed",
+		inSchema:  exampleoc.Schema,
+		inCurrent: attrSetDevice([]uint32{1, 2}),
+		inDesired: attrSetDevice([]uint32{3}),
+		wantReplaces: []string{
+			"/network-instances/network-instance[name=default]/protocols/protocol[identifier=BGP][name=15169]/bgp/rib/attr-sets/attr-set[index=1]",
+		},
+	}, {
+		desc:      "keyless list removed",
+		inSchema:  exampleoc.Schema,
+		inCurrent: attrSetDevice([]uint32{1, 2}),
+		inDesired: attrSetDevice(),
+		wantDeletes: []string{
+			"/network-instances/network-instance[name=default]/protocols/protocol[identifier=BGP][name=15169]/bgp/rib/attr-sets/attr-set[index=1]/as-path/as-segment",
+		},
+	}, {
+		desc:     "keyless list within root changed",
+		inSchema: exampleoc.Schema,
+		inCurrent: &exampleoc.NetworkInstance_Protocol_Bgp_Rib_AttrSet{
+			Index:     ygot.Uint64(1),
+			AsSegment: []*exampleoc.NetworkInstance_Protocol_Bgp_Rib_AttrSet_AsSegment{{Member: []uint32{1, 2}}},
+		},
+		inDesired: &exampleoc.NetworkInstance_Protocol_Bgp_Rib_AttrSet{
+			Index:     ygot.Uint64(1),
+			AsSegment: []*exampleoc.NetworkInstance_Protocol_Bgp_Rib_AttrSet_AsSegment{{Member: []uint32{3}}},
+		},
+		wantDeletes: []string{"/as-path/as-segment"},
+		wantUpdates: []string{"/"},
+	}, {
+		desc:      "keyless list unchanged",
+		inSchema:  exampleoc.Schema,
+		inCurrent: attrSetDevice([]uint32{1, 2}),
+		inDesired: attrSetDevice([]uint32{1, 2}),
+	}, {
+		desc:      "ordered list entries added and removed",
+		inSchema:  ctestschema.Schema,
+		inCurrent: &ctestschema.Device{OrderedList: orderedMap("foo", "qux", "bar")},
+		inDesired: &ctestschema.Device{OrderedList: orderedMap("foo", "bar", "baz")},
+		wantDeletes: []string{
+			"/ordered-lists/ordered-list[key=qux]",
+		},
+		wantReplaces: []string{
+			"/ordered-lists/ordered-list[key=baz]",
+		},
+	}, {
+		desc:      "ordered list reordered",
+		inSchema:  ctestschema.Schema,
+		inCurrent: &ctestschema.Device{OrderedList: orderedMap("foo", "bar")},
+		inDesired: &ctestschema.Device{OrderedList: orderedMap("bar", "foo")},
+		wantDeletes: []string{
+			"/ordered-lists",
+		},
+		wantUpdates: []string{"/"},
+	}, {
+		desc:      "different types",
+		inSchema:  exampleoc.Schema,
+		inCurrent: &exampleoc.Device{},
+		inDesired: &exampleoc.System{},
+		wantErr:   true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			schema, err := tt.inSchema()
+			if err != nil {
+				t.Fatalf("cannot load schema: %v", err)
+			}
+
+			got, err := ytypes.MinimalSetRequest(schema, tt.inCurrent, tt.inDesired)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MinimalSetRequest: got error: %v, want error? %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tt.wantDeletes, pathStrings(t, got.Delete)); diff != "" {
+				t.Errorf("MinimalSetRequest: deletes (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantReplaces, updatePathStrings(t, got.Replace)); diff != "" {
+				t.Errorf("MinimalSetRequest: replaces (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantUpdates, updatePathStrings(t, got.Update)); diff != "" {
+				t.Errorf("MinimalSetRequest: updates (-want, +got):\n%s", diff)
+			}
+
+			// Check that applying the SetRequest to current results in desired.
+			cur, err := ygot.DeepCopy(tt.inCurrent)
+			if err != nil {
+				t.Fatalf("cannot copy current: %v", err)
+			}
+			schema.Root = cur
+			if err := ytypes.UnmarshalSetRequest(schema, got); err != nil {
+				t.Fatalf("cannot apply SetRequest %v: %v", got, err)
+			}
+			gotJSON, err := ygot.Marshal7951(schema.Root)
+			if err != nil {
+				t.Fatalf("cannot marshal result: %v", err)
+			}
+			wantJSON, err := ygot.Marshal7951(tt.inDesired)
+			if err != nil {
+				t.Fatalf("cannot marshal desired: %v", err)
+			}
+			if diff := cmp.Diff(string(wantJSON), string(gotJSON)); diff != "" {
+				t.Errorf("applying SetRequest did not result in desired struct (-want, +got):\n%s", diff)
+			}
+
+			// A second SetRequest from the result to desired must be empty.
+			again, err := ytypes.MinimalSetRequest(schema, schema.Root, tt.inDesired)
+			if err != nil {
+				t.Fatalf("MinimalSetRequest after applying: %v", err)
+			}
+			if diff := cmp.Diff(&gpb.SetRequest{}, again, protocmp.Transform()); diff != "" {
+				t.Errorf("MinimalSetRequest after applying: got non-empty SetRequest (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func pathStrings(t *testing.T, paths []*gpb.Path) []string {
+	t.Helper()
+	var out []string
+	for _, p := range paths {
+		s, err := ygot.PathToString(p)
+		if err != nil {
+			t.Fatalf("cannot convert path %v to string: %v", p, err)
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func updatePathStrings(t *testing.T, updates []*gpb.Update) []string {
+	t.Helper()
+	var paths []*gpb.Path
+	for _, u := range updates {
+		paths = append(paths, u.Path)
+	}
+	return pathStrings(t, paths)
+}
@@ -475,6 +475,19 @@ func TestValidateNetworkInstance(t *testing.T) {
 	} else {
 		testErrLog(t, "bad element key field", err)
 	}
+
+	// Enumerated key leaf is unset in both the key and the element.
+	dev.NetworkInstance["instance1"].Protocol = map[oc.NetworkInstance_Protocol_Key]*oc.NetworkInstance_Protocol{
+		{Name: "protocol2"}: {
+			Name: ygot.String("protocol2"),
+		},
+	}
+	wantErr := "is missing key leaf identifier"
+	if err := dev.ΛValidate(); err == nil || !strings.Contains(err.Error(), wantErr) {
+		t.Errorf("unset enumerated key: got %v, want error containing %q", err, wantErr)
+	} else {
+		testErrLog(t, "unset enumerated key", err)
+	}
 }
 
 func TestValidateBGP(t *testing.T) {
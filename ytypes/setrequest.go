@@ -0,0 +1,463 @@
+// Copyright 2023 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/goyang/pkg/yang"
+	"github.com/openconfig/ygot/internal/yreflect"
+	"github.com/openconfig/ygot/util"
+	"github.com/openconfig/ygot/ygot"
+
+	gpb "github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// MinimalSetRequest returns a SetRequest that, when applied to current,
+// results in desired. current and desired must be GoStructs of the same type,
+// whose schema is found within the supplied schema's SchemaTree.
+//
+// The returned SetRequest is minimal in the number of operations it contains:
+//   - A container or list entry that has data in desired but none in current
+//     is added using a single JSON_IETF replace of the entire subtree.
+//   - A container, list entry or leaf that has data in current but none in
+//     desired is removed using a single delete of the entire subtree.
+//   - A leaf or leaf-list whose value differs between current and desired is
+//     updated using a scalar update.
+//   - A keyless list that differs between current and desired, or an ordered
+//     list in which the relative order of the entries present in both differs
+//     or in which new entries are not appended to the end, cannot be modified
+//     entry by entry. Such a list is deleted, and then set using a single
+//     JSON_IETF update of the container or list entry that holds it.
+//   - A container or list entry for which the above would result in more
+//     than one operation is instead set using a single JSON_IETF replace of
+//     the entire subtree. Since a replace of the root merges with, rather
+//     than replaces, its existing contents, this does not apply to the root.
+//
+// Operations are emitted in schema order, with the entries of keyed lists
+// sorted by key, such that the output is deterministic. Where the generated
+// GoStructs have compressed paths, the shortest path for each field is used.
+func MinimalSetRequest(schema *Schema, current, desired ygot.GoStruct) (*gpb.SetRequest, error) {
+	if schema == nil || schema.SchemaTree == nil {
+		return nil, fmt.Errorf("nil schema supplied")
+	}
+	if reflect.TypeOf(current) != reflect.TypeOf(desired) {
+		return nil, fmt.Errorf("cannot compute SetRequest between different types, current: %T, desired: %T", current, desired)
+	}
+	tn := reflect.TypeOf(desired).Elem().Name()
+	rootSchema, ok := schema.SchemaTree[tn]
+	if !ok {
+		return nil, fmt.Errorf("could not find schema for type %s", tn)
+	}
+
+	req := &gpb.SetRequest{}
+	if err := minimalSetStruct(rootSchema, reflect.ValueOf(current), reflect.ValueOf(desired), &gpb.Path{}, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// minimalSetStruct appends the operations required to transform the
+// GoStruct pointer cur into the GoStruct pointer des, both described by
+// schema and found at path, to req. Either of cur or des may be nil.
+func minimalSetStruct(schema *yang.Entry, cur, des reflect.Value, path *gpb.Path, req *gpb.SetRequest) error {
+	curData, desData := hasData(cur), hasData(des)
+	switch {
+	case !curData && !desData:
+		return nil
+	case !desData:
+		req.Delete = append(req.Delete, path)
+		return nil
+	case !curData && len(path.Elem) != 0:
+		return appendJSONReplace(req, path, des.Interface())
+	}
+
+	// Operations for the fields of the struct are collected separately, such
+	// that they can be discarded if the struct is replaced as a whole.
+	fieldReq := &gpb.SetRequest{}
+	t := des.Elem().Type()
+	for i := 0; i < t.NumField(); i++ {
+		ft := t.Field(i)
+		if util.IsYgotAnnotation(ft) {
+			continue
+		}
+		cschema, err := util.ChildSchema(schema, ft)
+		if err != nil {
+			return fmt.Errorf("cannot find schema for field %s of %s: %v", ft.Name, t.Name(), err)
+		}
+		if cschema == nil {
+			return fmt.Errorf("nil schema for field %s of %s", ft.Name, t.Name())
+		}
+		fpath, err := fieldPath(path, ft)
+		if err != nil {
+			return err
+		}
+
+		var cv reflect.Value
+		if cur.IsValid() && !cur.IsNil() {
+			cv = cur.Elem().Field(i)
+		}
+		dv := des.Elem().Field(i)
+
+		switch {
+		case cschema.IsLeaf() || cschema.IsLeafList():
+			err = minimalSetLeaf(cv, dv, fpath, fieldReq)
+		case cschema.IsList() && dv.Kind() == reflect.Slice:
+			if keylessListEqual(cv, dv) {
+				break
+			}
+			// The entries of a keyless list cannot be addressed, so the
+			// list is deleted and then set as a whole.
+			fieldReq.Delete = append(fieldReq.Delete, fpath)
+			if hasData(dv) {
+				err = appendFieldJSONUpdate(fieldReq, path, des, i)
+			}
+		case cschema.IsList():
+			var setWhole bool
+			if setWhole, err = minimalSetList(cschema, cv, dv, path, fpath, fieldReq); err == nil && setWhole {
+				err = appendFieldJSONUpdate(fieldReq, path, des, i)
+			}
+		default:
+			err = minimalSetStruct(cschema, fieldOrNil(cv, dv.Type()), dv, fpath, fieldReq)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	// A single replace of the struct is used in place of multiple operations
+	// on its fields. A replace of the root merges with its existing contents,
+	// so cannot be used in this way.
+	if len(path.Elem) != 0 && len(fieldReq.Delete)+len(fieldReq.Replace)+len(fieldReq.Update) > 1 {
+		return appendJSONReplace(req, path, des.Interface())
+	}
+	req.Delete = append(req.Delete, fieldReq.Delete...)
+	req.Replace = append(req.Replace, fieldReq.Replace...)
+	req.Update = append(req.Update, fieldReq.Update...)
+	return nil
+}
+
+// keylessListEqual reports whether the keyless lists cur and des, represented
+// as slices, contain the same entries in the same order.
+func keylessListEqual(cur, des reflect.Value) bool {
+	curData, desData := hasData(cur), hasData(des)
+	if !curData || !desData {
+		return curData == desData
+	}
+	return reflect.DeepEqual(cur.Interface(), des.Interface())
+}
+
+// minimalSetLeaf appends the operation required to transform the leaf or
+// leaf-list value cur into des at path to req.
+func minimalSetLeaf(cur, des reflect.Value, path *gpb.Path, req *gpb.SetRequest) error {
+	curData, desData := hasData(cur), hasData(des)
+	switch {
+	case !curData && !desData:
+		return nil
+	case !desData:
+		req.Delete = append(req.Delete, path)
+		return nil
+	case curData && reflect.DeepEqual(cur.Interface(), des.Interface()):
+		return nil
+	}
+
+	v, err := ygot.EncodeTypedValue(des.Interface(), gpb.Encoding_PROTO)
+	if err != nil {
+		return fmt.Errorf("cannot represent field value %v as TypedValue for path %v: %v", util.ValueStr(des.Interface()), path, err)
+	}
+	req.Update = append(req.Update, &gpb.Update{Path: path, Val: v})
+	return nil
+}
+
+// minimalSetList appends the operations required to transform the keyed list
+// cur into des at path, within the struct at parent, to req. Lists may be
+// represented as either a map or a GoOrderedList. It returns true if the
+// order of the entries of des cannot be achieved by modifying individual
+// entries, in which case the entries of cur are deleted and the caller must
+// set the list as a whole.
+func minimalSetList(schema *yang.Entry, cur, des reflect.Value, parent, path *gpb.Path, req *gpb.SetRequest) (bool, error) {
+	curData, desData := hasData(cur), hasData(des)
+	switch {
+	case !curData && !desData:
+		return false, nil
+	case !desData:
+		return false, appendListDelete(req, cur, parent, path)
+	}
+
+	curKeys, curElems, err := listEntries(fieldOrNil(cur, des.Type()))
+	if err != nil {
+		return false, err
+	}
+	desKeys, desElems, err := listEntries(des)
+	if err != nil {
+		return false, err
+	}
+
+	if _, ok := des.Interface().(ygot.GoOrderedList); ok && curData && !orderPreserved(curKeys, desKeys, desElems) {
+		// The order of existing entries within an ordered list can only be
+		// changed by removing all entries and then adding them back in the
+		// desired order.
+		return true, appendListDelete(req, cur, parent, path)
+	}
+
+	for _, k := range curKeys {
+		if _, ok := desElems[k]; !ok {
+			req.Delete = append(req.Delete, listEntryPath(path, curElems[k]))
+		}
+	}
+	for _, k := range desKeys {
+		cv, ok := curElems[k]
+		if !ok {
+			cv = reflect.Zero(desElems[k].Type())
+		}
+		if err := minimalSetStruct(schema, cv, desElems[k], listEntryPath(path, desElems[k]), req); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// appendListDelete appends the deletes required to remove all entries of the
+// keyed list cur at path, within the struct at parent, to req. Where cur is
+// an ordered list within a surrounding container that is compressed out of
+// the generated code, the surrounding container, which holds only the list,
+// is deleted. Otherwise, since a list cannot be deleted by a path without
+// keys, each entry is deleted.
+func appendListDelete(req *gpb.SetRequest, cur reflect.Value, parent, path *gpb.Path) error {
+	if _, ok := cur.Interface().(ygot.GoOrderedList); ok && len(path.Elem)-len(parent.Elem) > 1 {
+		req.Delete = append(req.Delete, &gpb.Path{Elem: path.Elem[:len(path.Elem)-1]})
+		return nil
+	}
+	keys, elems, err := listEntries(cur)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		req.Delete = append(req.Delete, listEntryPath(path, elems[k]))
+	}
+	return nil
+}
+
+// listEntries returns the keys and element values of the list v, which is
+// either a map or a GoOrderedList. The returned keys are the gNMI path keys
+// of each element, and are in the order of the list for a GoOrderedList.
+func listEntries(v reflect.Value) ([]string, map[string]reflect.Value, error) {
+	elems := map[string]reflect.Value{}
+	var keys []string
+	if util.IsNilOrInvalidValue(v) {
+		return nil, elems, nil
+	}
+
+	addElem := func(e reflect.Value) error {
+		k, err := listEntryKey(e)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, k)
+		elems[k] = e
+		return nil
+	}
+
+	if om, ok := v.Interface().(ygot.GoOrderedList); ok {
+		var err error
+		if rangeErr := yreflect.RangeOrderedMap(om, func(_ reflect.Value, e reflect.Value) bool {
+			err = addElem(e)
+			return err == nil
+		}); rangeErr != nil {
+			return nil, nil, rangeErr
+		}
+		return keys, elems, err
+	}
+
+	for _, mk := range v.MapKeys() {
+		if err := addElem(v.MapIndex(mk)); err != nil {
+			return nil, nil, err
+		}
+	}
+	sort.Strings(keys)
+	return keys, elems, nil
+}
+
+// listEntryKey returns a string that uniquely identifies the list element e
+// by its key values.
+func listEntryKey(e reflect.Value) (string, error) {
+	keys, err := listEntryKeys(e)
+	if err != nil {
+		return "", err
+	}
+	var names []string
+	for k := range keys {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, k := range names {
+		fmt.Fprintf(&b, "[%s=%s]", k, keys[k])
+	}
+	return b.String(), nil
+}
+
+// listEntryKeys returns the gNMI path keys of the list element e.
+func listEntryKeys(e reflect.Value) (map[string]string, error) {
+	kh, ok := e.Interface().(ygot.KeyHelperGoStruct)
+	if !ok {
+		return nil, fmt.Errorf("list element %T does not implement KeyHelperGoStruct", e.Interface())
+	}
+	km, err := kh.ΛListKeyMap()
+	if err != nil {
+		return nil, fmt.Errorf("cannot retrieve keys of %T: %v", e.Interface(), err)
+	}
+	keys := map[string]string{}
+	for k, v := range km {
+		s, err := ygot.KeyValueAsString(v)
+		if err != nil {
+			return nil, fmt.Errorf("cannot convert key %s of %T to string: %v", k, e.Interface(), err)
+		}
+		keys[k] = s
+	}
+	return keys, nil
+}
+
+// listEntryPath returns the path of the list element e within the list at
+// path. The key of e is assumed to be retrievable, since it was previously
+// retrieved by listEntries.
+func listEntryPath(path *gpb.Path, e reflect.Value) *gpb.Path {
+	keys, _ := listEntryKeys(e)
+	p := &gpb.Path{Elem: append([]*gpb.PathElem{}, path.Elem[:len(path.Elem)-1]...)}
+	last := path.Elem[len(path.Elem)-1]
+	p.Elem = append(p.Elem, &gpb.PathElem{Name: last.Name, Key: keys})
+	return p
+}
+
+// orderPreserved reports whether the keys of the ordered list cur that remain
+// in des (whose elements are desElems) form a prefix of des, such that des can
+// be achieved by deleting entries from cur and appending new entries.
+func orderPreserved(cur, des []string, desElems map[string]reflect.Value) bool {
+	i := 0
+	for _, k := range cur {
+		if _, ok := desElems[k]; !ok {
+			continue
+		}
+		if i >= len(des) || des[i] != k {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+// fieldPath returns the path of the struct field ft within the parent at
+// path. Where more than one path is specified for the field, the shortest is
+// used.
+func fieldPath(path *gpb.Path, ft reflect.StructField) (*gpb.Path, error) {
+	sp, err := util.SchemaPaths(ft)
+	if err != nil {
+		return nil, err
+	}
+	if len(sp) == 0 {
+		return nil, fmt.Errorf("invalid schema path for %s", ft.Name)
+	}
+	shortest := sp[0]
+	for _, p := range sp[1:] {
+		if len(p) < len(shortest) {
+			shortest = p
+		}
+	}
+	p := &gpb.Path{Elem: append([]*gpb.PathElem{}, path.Elem...)}
+	for _, e := range shortest {
+		p.Elem = append(p.Elem, &gpb.PathElem{Name: e})
+	}
+	return p, nil
+}
+
+// fieldOrNil returns v if it is valid, or the zero value of type t otherwise.
+func fieldOrNil(v reflect.Value, t reflect.Type) reflect.Value {
+	if !v.IsValid() {
+		return reflect.Zero(t)
+	}
+	return v
+}
+
+// appendJSONReplace appends a replace of the value at path with the
+// JSON_IETF encoding of v to req.
+func appendJSONReplace(req *gpb.SetRequest, path *gpb.Path, v interface{}) error {
+	tv, err := ygot.EncodeTypedValue(v, gpb.Encoding_JSON_IETF, &ygot.RFC7951JSONConfig{})
+	if err != nil {
+		return fmt.Errorf("cannot encode value for path %v as JSON: %v", path, err)
+	}
+	req.Replace = append(req.Replace, &gpb.Update{Path: path, Val: tv})
+	return nil
+}
+
+// appendFieldJSONUpdate appends an update of the struct at path with the
+// JSON_IETF encoding of a copy of the GoStruct pointer des in which only the
+// field with index i is populated to req.
+func appendFieldJSONUpdate(req *gpb.SetRequest, path *gpb.Path, des reflect.Value, i int) error {
+	v := reflect.New(des.Elem().Type())
+	v.Elem().Field(i).Set(des.Elem().Field(i))
+	tv, err := ygot.EncodeTypedValue(v.Interface(), gpb.Encoding_JSON_IETF, &ygot.RFC7951JSONConfig{})
+	if err != nil {
+		return fmt.Errorf("cannot encode field %s for path %v as JSON: %v", des.Elem().Type().Field(i).Name, path, err)
+	}
+	req.Update = append(req.Update, &gpb.Update{Path: path, Val: tv})
+	return nil
+}
+
+// hasData reports whether the value v contains any data. Unset enumerated
+// values, unset YANG empty leaves, nil pointers and empty containers, lists
+// and leaf-lists are considered to contain no data.
+func hasData(v reflect.Value) bool {
+	if util.IsNilOrInvalidValue(v) {
+		return false
+	}
+	if om, ok := v.Interface().(ygot.GoOrderedList); ok {
+		return om.Len() != 0
+	}
+	if _, ok := v.Interface().(ygot.GoEnum); ok {
+		if v.Kind() == reflect.Interface {
+			v = v.Elem()
+		}
+		return v.Int() != 0
+	}
+
+	if v.Kind() == reflect.Bool && v.Type().Name() == ygot.EmptyTypeName {
+		return v.Bool()
+	}
+
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice:
+		return v.Len() != 0
+	case reflect.Ptr:
+		if !util.IsValueStructPtr(v) {
+			return true
+		}
+		if _, ok := v.Interface().(ygot.GoStruct); !ok {
+			// Union wrapper types are represented as struct pointers.
+			return true
+		}
+		for i := 0; i < v.Elem().NumField(); i++ {
+			if util.IsYgotAnnotation(v.Elem().Type().Field(i)) {
+				continue
+			}
+			if hasData(v.Elem().Field(i)) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
@@ -43,6 +43,17 @@ func (*IgnoreExtraFields) IsUnmarshalOpt() {}
 // See PreferShadowPath's definition in node.go.
 func (*PreferShadowPath) IsUnmarshalOpt() {}
 
+// StripJSONComments is an unmarshal option that specifies that the input
+// JSON may contain "//" line comments and "/* */" block comments, which
+// should be removed prior to the JSON being decoded. Comment delimiters that
+// appear within JSON string values are preserved. The option only has an
+// effect on functions that take raw JSON bytes as input, such as the
+// generated Unmarshal function, via PreprocessJSON.
+type StripJSONComments struct{}
+
+// IsUnmarshalOpt marks StripJSONComments as a valid UnmarshalOpt.
+func (*StripJSONComments) IsUnmarshalOpt() {}
+
 // Unmarshal recursively unmarshals JSON data tree in value into the given
 // parent, using the given schema. Any values already in the parent that are
 // not present in value are preserved. If provided schema is a leaf or leaf
@@ -51,6 +62,66 @@ func Unmarshal(schema *yang.Entry, parent interface{}, value interface{}, opts .
 	return unmarshalGeneric(schema, parent, value, JSONEncoding, opts...)
 }
 
+// PreprocessJSON applies the supplied UnmarshalOpts that operate on raw JSON
+// input to data, returning the JSON that should be decoded prior to calling
+// Unmarshal. If no such options are specified, data is returned unmodified.
+func PreprocessJSON(data []byte, opts ...UnmarshalOpt) ([]byte, error) {
+	if hasStripJSONComments(opts) {
+		return stripJSONComments(data)
+	}
+	return data, nil
+}
+
+// stripJSONComments returns a copy of data with all "//" line comments and
+// "/* */" block comments replaced by whitespace. Newlines within comments are
+// retained such that the line numbers reported by the JSON decoder are
+// unchanged. Characters within JSON string values are never treated as the
+// start of a comment. An error is returned if a block comment is not
+// terminated.
+func stripJSONComments(data []byte) ([]byte, error) {
+	out := make([]byte, len(data))
+	copy(out, data)
+
+	blank := func(i int) {
+		if out[i] != '\n' && out[i] != '\r' {
+			out[i] = ' '
+		}
+	}
+
+	for i := 0; i < len(out); i++ {
+		switch {
+		case out[i] == '"':
+			// Skip to the end of the string, honouring escaped characters.
+			for i++; i < len(out) && out[i] != '"'; i++ {
+				if out[i] == '\\' {
+					i++
+				}
+			}
+		case out[i] == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				blank(i)
+			}
+		case out[i] == '/' && i+1 < len(out) && out[i+1] == '*':
+			start := i
+			blank(i)
+			blank(i + 1)
+			for i += 2; ; i++ {
+				if i+1 >= len(out) {
+					return nil, fmt.Errorf("unterminated block comment starting at offset %d", start)
+				}
+				if out[i] == '*' && out[i+1] == '/' {
+					blank(i)
+					blank(i + 1)
+					i++
+					break
+				}
+				blank(i)
+			}
+		}
+	}
+	return out, nil
+}
+
 // Encoding specifies how the value provided to UnmarshalGeneric function is encoded.
 type Encoding int
 
@@ -123,3 +194,14 @@ func hasPreferShadowPath(opts []UnmarshalOpt) bool {
 	}
 	return false
 }
+
+// hasStripJSONComments determines whether the supplied slice of UnmarshalOpts
+// contains the StripJSONComments option.
+func hasStripJSONComments(opts []UnmarshalOpt) bool {
+	for _, o := range opts {
+		if _, ok := o.(*StripJSONComments); ok {
+			return true
+		}
+	}
+	return false
+}
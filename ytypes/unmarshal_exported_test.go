@@ -0,0 +1,62 @@
+// Copyright 2023 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ytypes_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/ygot/integration_tests/schemaops/ctestschema"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/openconfig/ygot/ytypes"
+)
+
+func TestUnmarshalStripJSONComments(t *testing.T) {
+	in := []byte(`
+// Desired state for the device.
+{
+  "unordered-lists": {
+    "unordered-list": [
+      {
+        "key": "foo", // the list key
+        /* The value contains a URL,
+           which must not be treated as a comment. */
+        "config": {"key": "foo", "value": "http://example.com/a//b"}
+      }
+    ]
+  }
+}`)
+
+	want := &ctestschema.Device{
+		UnorderedList: map[string]*ctestschema.UnorderedList{
+			"foo": {
+				Key:   ygot.String("foo"),
+				Value: ygot.String("http://example.com/a//b"),
+			},
+		},
+	}
+
+	if err := ctestschema.Unmarshal(in, &ctestschema.Device{}); err == nil {
+		t.Errorf("Unmarshal without StripJSONComments: got no error, want error")
+	}
+
+	got := &ctestschema.Device{}
+	if err := ctestschema.Unmarshal(in, got, &ytypes.StripJSONComments{}); err != nil {
+		t.Fatalf("Unmarshal with StripJSONComments: got unexpected error: %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unmarshal with StripJSONComments: (-want, +got):\n%s", diff)
+	}
+}
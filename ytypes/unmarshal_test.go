@@ -79,3 +79,52 @@ func TestUnmarshal(t *testing.T) {
 		})
 	}
 }
+
+func TestPreprocessJSON(t *testing.T) {
+	tests := []struct {
+		desc    string
+		in      string
+		opts    []UnmarshalOpt
+		want    string
+		wantErr string
+	}{{
+		desc: "no options",
+		in:   `{"a": "b"} // comment`,
+		want: `{"a": "b"} // comment`,
+	}, {
+		desc: "line comments",
+		in:   "// leading\n{\"a\": \"b\" // trailing\n}",
+		opts: []UnmarshalOpt{&StripJSONComments{}},
+		want: "          \n{\"a\": \"b\"            \n}",
+	}, {
+		desc: "block comments",
+		in:   "{/* one */\"a\": /* multi\nline */ \"b\"}",
+		opts: []UnmarshalOpt{&StripJSONComments{}},
+		want: "{         \"a\":         \n        \"b\"}",
+	}, {
+		desc: "comment delimiters within strings",
+		in:   `{"url": "http://example.com/*x*/", "q": "\"//"} // comment`,
+		opts: []UnmarshalOpt{&StripJSONComments{}},
+		want: `{"url": "http://example.com/*x*/", "q": "\"//"}           `,
+	}, {
+		desc:    "unterminated block comment",
+		in:      `{"a": "b"} /* comment`,
+		opts:    []UnmarshalOpt{&StripJSONComments{}},
+		wantErr: "unterminated block comment starting at offset 11",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := PreprocessJSON([]byte(tt.in), tt.opts...)
+			if gotErr := errToString(err); gotErr != tt.wantErr {
+				t.Fatalf("got error: %v, want error: %v", gotErr, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("got:\n%q\nwant:\n%q", got, tt.want)
+			}
+		})
+	}
+}